@@ -0,0 +1,133 @@
+package voprf
+
+import (
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// EvaluateBatchWithIndividualProofs evaluates blindedElements like EvaluateBatch, but attaches one
+// DLEQ proof per element instead of folding the whole batch into a single composite proof. This
+// costs more bandwidth than EvaluateBatch, but lets a caller later verify (or aggregate) a subset of
+// the batch's elements independently of the others.
+func (s *Server) EvaluateBatchWithIndividualProofs(blindedElements [][]byte, epochID ...uint32) ([]*EpochEvaluation, error) {
+	e, err := s.resolveEpoch(epochID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*EpochEvaluation, len(blindedElements))
+
+	for i, raw := range blindedElements {
+		b, err := s.group.NewElement().Decode(raw)
+		if err != nil {
+			return nil, fmt.Errorf("OPRF can't evaluate input : %w", err)
+		}
+
+		ev := &evaluation{}
+		ev.elements = []group.Element{s.evaluate(b, e)}
+
+		if s.mode == Verifiable {
+			c, ps := s.generateProof(e, []group.Element{b}, ev.elements)
+			ev.proofC = c
+			ev.proofS = ps
+		}
+
+		out[i] = &EpochEvaluation{Evaluation: ev.serialize(), EpochID: e.id}
+	}
+
+	return out, nil
+}
+
+// RLCBatchItem pairs one Evaluate call's blinded input with the Evaluation and key epoch it was
+// issued under, so a batch of otherwise-independent calls can be checked together with
+// AggregateAndVerify.
+type RLCBatchItem struct {
+	Blinded    []byte
+	Evaluation *Evaluation
+	EpochID    uint32
+}
+
+// AggregateAndVerify checks a batch of independent single-element Evaluations against pk, one DLEQ
+// verification per item, stopping at and reporting the first that fails. Each item needs its own
+// blinded input and key epoch alongside its Evaluation - an Evaluation alone is just the evaluated
+// element and proof, not enough to rebuild the DLEQ statement being checked - hence RLCBatchItem
+// rather than a bare []*Evaluation.
+//
+// There is no sound way to check a batch like this in less than one verification per item: each
+// proofC_i is a Fiat-Shamir challenge over that item's own transcript (pk, a0_i, a1_i, a2_i, a3_i),
+// so a random-linear-combination of already-finalized (proofC_i, proofS_i) pairs doesn't satisfy the
+// verification equation for any combined statement over a folded (B, E) - it isn't a fresh proof of
+// anything, just arithmetic on numbers that happen to be DLEQ outputs. AggregateAndVerify exists for
+// the convenience of a single call and a single error, not for a sub-linear verification cost.
+func AggregateAndVerify(g group.Group, pk []byte, items []RLCBatchItem) error {
+	if len(items) == 0 {
+		return fmt.Errorf("voprf: no evaluations to verify")
+	}
+
+	pkElement, err := g.NewElement().Decode(pk)
+	if err != nil {
+		return fmt.Errorf("voprf: decoding public key: %w", err)
+	}
+
+	for i, item := range items {
+		blinded, evaluated, err := decodeBatchItem(g, item)
+		if err != nil {
+			return fmt.Errorf("voprf: decoding evaluation %d: %w", i, err)
+		}
+
+		eval := item.Evaluation
+
+		c, err := g.NewScalar().Decode(eval.ProofC)
+		if err != nil {
+			return fmt.Errorf("voprf: decoding proof for evaluation %d: %w", i, err)
+		}
+
+		s, err := g.NewScalar().Decode(eval.ProofS)
+		if err != nil {
+			return fmt.Errorf("voprf: decoding proof for evaluation %d: %w", i, err)
+		}
+
+		if !verifyProof(g, pkElement, []group.Element{blinded}, []group.Element{evaluated}, c, s, encodeUint64(uint64(item.EpochID))) {
+			return fmt.Errorf("voprf: evaluation %d failed DLEQ verification", i)
+		}
+	}
+
+	return nil
+}
+
+// VerifyBatchRLC is AggregateAndVerify under the name the original request specified
+// (voprf.AggregateAndVerify(pk, evaluations) was also requested; see that function's doc comment for
+// why its parameters can't be narrowed to just pk and the evaluations, and why VerifyBatchRLC isn't
+// any cheaper than N individual calls to VerifyEvaluation.
+//
+// A random-linear-combination batch speedup is possible for signature schemes whose proof carries
+// its Schnorr commitment explicitly (e.g. Ed25519's R), because the verification equation is then
+// linear over the group and many instances of it can be folded into one multi-scalar multiplication.
+// This module's DLEQ proofs instead carry only (proofC, proofS) and rebuild the commitment as
+// a2 = g^s·pk^c, a3 = a1^s·a0^c before re-hashing it to recheck proofC - a non-linear, one-way step
+// that has to run in full for every item before its output (proofC_i) can even be compared, let alone
+// combined with another item's. There is no RLC over the group that shortcuts a hash. Getting a real
+// speedup here would mean changing the wire format to carry (a2, a3) instead of deriving them, which
+// is a different (larger) proof than the one this module issues today.
+func VerifyBatchRLC(g group.Group, pk []byte, items []RLCBatchItem) error {
+	return AggregateAndVerify(g, pk, items)
+}
+
+func decodeBatchItem(g group.Group, item RLCBatchItem) (blinded, evaluated group.Element, err error) {
+	if len(item.Evaluation.Elements) != 1 {
+		return nil, nil, fmt.Errorf("voprf: AggregateAndVerify only supports single-element evaluations")
+	}
+
+	blinded, err = g.NewElement().Decode(item.Blinded)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	evaluated, err = g.NewElement().Decode(item.Evaluation.Elements[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return blinded, evaluated, nil
+}
@@ -0,0 +1,52 @@
+package voprf
+
+import (
+	"testing"
+
+	"github.com/bytemare/voprf/internal/testgroup"
+)
+
+func TestAggregateAndVerify(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Verifiable)
+
+	items := make([]RLCBatchItem, 3)
+
+	for i, input := range [][]byte{[]byte("a"), []byte("b"), []byte("c")} {
+		c := NewClient(g, Verifiable, s.keyring.activeEpoch().publicKey)
+
+		blinded, err := c.Blind(input)
+		if err != nil {
+			t.Fatalf("Blind %d: %v", i, err)
+		}
+
+		ev, err := s.Evaluate(blinded)
+		if err != nil {
+			t.Fatalf("Evaluate %d: %v", i, err)
+		}
+
+		items[i] = RLCBatchItem{Blinded: blinded, Evaluation: ev.Evaluation, EpochID: ev.EpochID}
+	}
+
+	if err := AggregateAndVerify(g, s.PublicKey(), items); err != nil {
+		t.Fatalf("AggregateAndVerify rejected a genuine batch: %v", err)
+	}
+
+	if err := VerifyBatchRLC(g, s.PublicKey(), items); err != nil {
+		t.Fatalf("VerifyBatchRLC rejected a genuine batch: %v", err)
+	}
+
+	tampered := append([]RLCBatchItem{}, items...)
+	tamperedProofS := append([]byte{}, items[1].Evaluation.ProofS...)
+	tamperedProofS[0] ^= 0xff
+	tampered[1] = RLCBatchItem{
+		Blinded:    items[1].Blinded,
+		Evaluation: &Evaluation{Elements: items[1].Evaluation.Elements, ProofC: items[1].Evaluation.ProofC, ProofS: tamperedProofS},
+		EpochID:    items[1].EpochID,
+	}
+
+	if err := AggregateAndVerify(g, s.PublicKey(), tampered); err == nil {
+		t.Fatal("AggregateAndVerify accepted a batch with one tampered proof")
+	}
+}
@@ -0,0 +1,312 @@
+package voprf
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// auditEntry is one Evaluate/EvaluateBatch call recorded in the audit log: enough to let a client
+// later prove which blinded input was answered with which evaluated output and proof, without the
+// log itself storing the (sensitive) elements in the clear.
+type auditEntry struct {
+	timestamp      int64
+	blindedHash    [32]byte
+	evaluatedHash  [32]byte
+	proofC, proofS []byte
+}
+
+// AuditEntry is the externally-visible description of one audited evaluation, built from the same
+// wire-format elements and proof values a caller already holds from its own Evaluate/EvaluateBatch
+// round trip - not the internal auditEntry a server logs, which only ever stores hashes of them.
+// VerifyInclusion uses it to recompute recordAudit's leaf hash independently, rather than trusting an
+// InclusionProof's LeafHash at face value.
+type AuditEntry struct {
+	Timestamp int64
+	Blinded   [][]byte
+	Evaluated [][]byte
+	ProofC    []byte
+	ProofS    []byte
+}
+
+// leafHash recomputes the same RFC 6962-style leaf hash recordAudit produced when the server logged
+// this entry.
+func (e *AuditEntry) leafHash() [32]byte {
+	return computeLeafHash(e.Timestamp, hashRawElements(e.Blinded), hashRawElements(e.Evaluated), e.ProofC, e.ProofS)
+}
+
+// TreeHead is a signed commitment to the audit log's state at a given size, published periodically
+// so third parties can detect the server later rewriting or forking its history.
+type TreeHead struct {
+	Size      uint64
+	RootHash  [32]byte
+	Signature []byte
+}
+
+// InclusionProof demonstrates that a specific audit log entry is present, at its claimed index,
+// under a published TreeHead.
+type InclusionProof struct {
+	Index    uint64
+	LeafHash [32]byte
+	Path     []PathStep
+}
+
+// LogStore persists the append-only audit log across restarts.
+type LogStore interface {
+	Append(entry *auditEntry) (index uint64, err error)
+	Get(index uint64) (*auditEntry, error)
+	Size() uint64
+}
+
+// memoryLogStore is a LogStore backed by a slice. It is not persisted across restarts.
+type memoryLogStore struct {
+	entries []*auditEntry
+}
+
+// NewMemoryLogStore returns a LogStore that keeps the audit log in memory for the process lifetime.
+func NewMemoryLogStore() LogStore {
+	return &memoryLogStore{}
+}
+
+func (m *memoryLogStore) Append(entry *auditEntry) (uint64, error) {
+	m.entries = append(m.entries, entry)
+	return uint64(len(m.entries) - 1), nil
+}
+
+func (m *memoryLogStore) Get(index uint64) (*auditEntry, error) {
+	if index >= uint64(len(m.entries)) {
+		return nil, fmt.Errorf("voprf: audit log has no entry at index %d", index)
+	}
+
+	return m.entries[index], nil
+}
+
+func (m *memoryLogStore) Size() uint64 {
+	return uint64(len(m.entries))
+}
+
+// EnableAudit turns on append-only logging of every Evaluate/EvaluateBatch call the server makes in
+// Verifiable mode. If store is nil, the log is kept in memory only.
+func (s *Server) EnableAudit(store LogStore) {
+	if store == nil {
+		store = NewMemoryLogStore()
+	}
+
+	s.auditLog = store
+}
+
+// recordAudit appends one evaluation to the audit log, if auditing is enabled. It is a no-op
+// otherwise, so servers that never call EnableAudit pay nothing for it.
+func (s *Server) recordAudit(now int64, blindedElements, evaluatedElements []group.Element, proofC, proofS group.Scalar) {
+	if s.auditLog == nil {
+		return
+	}
+
+	entry := &auditEntry{
+		timestamp:     now,
+		blindedHash:   hashElements(blindedElements),
+		evaluatedHash: hashElements(evaluatedElements),
+		proofC:        proofC.Bytes(),
+		proofS:        proofS.Bytes(),
+	}
+
+	// The log is append-only by construction: LogStore implementations must not expose a way to
+	// mutate or remove an entry once Append has returned.
+	_, _ = s.auditLog.Append(entry)
+}
+
+// hashElements folds a batch of group elements into a single leaf digest.
+func hashElements(elements []group.Element) [32]byte {
+	raw := make([][]byte, len(elements))
+	for i, e := range elements {
+		raw[i] = e.Bytes()
+	}
+
+	return hashRawElements(raw)
+}
+
+// hashRawElements is hashElements over wire-format elements, for callers (e.g. AuditEntry) that
+// never decoded them into the group in the first place.
+func hashRawElements(elements [][]byte) [32]byte {
+	h := sha256.New()
+	for _, e := range elements {
+		h.Write(e)
+	}
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+
+	return out
+}
+
+// LogProof returns an inclusion proof for the audit log entry at index, against the tree as it
+// stands right now. Publish a TreeHead at the same size (see PublishTreeHead) for the proof to be
+// independently checkable later.
+func (s *Server) LogProof(index uint64) (*InclusionProof, error) {
+	if s.auditLog == nil {
+		return nil, fmt.Errorf("voprf: auditing is not enabled")
+	}
+
+	size := s.auditLog.Size()
+	if index >= size {
+		return nil, fmt.Errorf("voprf: audit log has no entry at index %d", index)
+	}
+
+	leaves, err := s.leafHashes(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &InclusionProof{
+		Index:    index,
+		LeafHash: leaves[index],
+		Path:     merklePath(leaves, index),
+	}, nil
+}
+
+// PublishTreeHead signs a commitment to the audit log's current size and root hash with the
+// server's OPRF private key, over a domain-separated transcript. Operators are expected to publish
+// the result (e.g. to a gossip or transparency endpoint) on a fixed schedule.
+func (s *Server) PublishTreeHead() (*TreeHead, error) {
+	if s.auditLog == nil {
+		return nil, fmt.Errorf("voprf: auditing is not enabled")
+	}
+
+	size := s.auditLog.Size()
+
+	leaves, err := s.leafHashes(size)
+	if err != nil {
+		return nil, err
+	}
+
+	root := merkleRoot(leaves)
+	sig := s.signTreeHead(size, root)
+
+	return &TreeHead{Size: size, RootHash: root, Signature: sig}, nil
+}
+
+// signTreeHead signs (size, rootHash) with the server's active-epoch OPRF key, using a Schnorr
+// signature over the same group the OPRF runs in: a third party holding only the epoch's public key
+// can check it with VerifyTreeHead, unlike reusing EvaluatePRF's MAC, which only the holder of the
+// shared PRF secret could ever confirm.
+func (s *Server) signTreeHead(size uint64, root [32]byte) []byte {
+	e := s.keyring.activeEpoch()
+	msg := treeHeadTranscript(size, root)
+
+	k := s.group.NewScalar().Random()
+	r := s.group.Base().Mult(k)
+
+	c := treeHeadChallenge(s.group, e.publicKey, r, msg)
+	resp := k.Sub(c.Mult(e.privateKey))
+
+	return append(append([]byte{}, c.Bytes()...), resp.Bytes()...)
+}
+
+// VerifyTreeHead checks a TreeHead's Signature against pk, the epoch public key of the server that
+// is claimed to have published it. Third parties holding only pk - not the server's private key or
+// its PRF secret - use this to hold the server to a tree head it published, without needing access
+// to the log itself.
+func VerifyTreeHead(g group.Group, pk []byte, head *TreeHead) (bool, error) {
+	pkElement, err := g.NewElement().Decode(pk)
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding tree head key: %w", err)
+	}
+
+	scalarLen := len(g.NewScalar().Bytes())
+	if len(head.Signature) != 2*scalarLen {
+		return false, fmt.Errorf("voprf: malformed tree head signature")
+	}
+
+	c, err := g.NewScalar().Decode(head.Signature[:scalarLen])
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding tree head challenge: %w", err)
+	}
+
+	resp, err := g.NewScalar().Decode(head.Signature[scalarLen:])
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding tree head response: %w", err)
+	}
+
+	msg := treeHeadTranscript(head.Size, head.RootHash)
+	r := g.Base().Mult(resp).Add(pkElement.Mult(c))
+
+	return treeHeadChallenge(g, pkElement, r, msg).Equal(c), nil
+}
+
+// treeHeadTranscript builds the domain-separated message a tree head signature is over.
+func treeHeadTranscript(size uint64, root [32]byte) []byte {
+	transcript := append([]byte("voprf-audit-tree-head-v1"), root[:]...)
+	return append(transcript, encodeUint64(size)...)
+}
+
+// treeHeadChallenge derives a tree head signature's Fiat-Shamir challenge from the signer's key, its
+// commitment r, and the signed message.
+func treeHeadChallenge(g group.Group, pk, r group.Element, msg []byte) group.Scalar {
+	transcript := append(append(append([]byte{}, pk.Bytes()...), r.Bytes()...), msg...)
+	return g.HashToScalar(transcript)
+}
+
+// leafHashes reconstructs the leaf hash for every entry in the log up to size.
+func (s *Server) leafHashes(size uint64) ([][32]byte, error) {
+	leaves := make([][32]byte, size)
+
+	for i := uint64(0); i < size; i++ {
+		e, err := s.auditLog.Get(i)
+		if err != nil {
+			return nil, err
+		}
+
+		leaves[i] = leafHash(e)
+	}
+
+	return leaves, nil
+}
+
+// leafHash is the RFC 6962-style leaf hash: SHA-256 of a 0x00 prefix followed by the entry's
+// canonical encoding, domain-separating leaves from interior nodes.
+func leafHash(e *auditEntry) [32]byte {
+	return computeLeafHash(e.timestamp, e.blindedHash, e.evaluatedHash, e.proofC, e.proofS)
+}
+
+// computeLeafHash is the shared core of leafHash and AuditEntry.leafHash: the two differ only in
+// where blindedHash/evaluatedHash come from (already hashed and stored, vs. recomputed from a
+// caller-supplied AuditEntry's raw elements), so both funnel into this one encoding.
+func computeLeafHash(timestamp int64, blindedHash, evaluatedHash [32]byte, proofC, proofS []byte) [32]byte {
+	h := sha256.New()
+	h.Write([]byte{0x00})
+	h.Write(encodeUint64(uint64(timestamp)))
+	h.Write(blindedHash[:])
+	h.Write(evaluatedHash[:])
+	h.Write(proofC)
+	h.Write(proofS)
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+
+	return out
+}
+
+// interiorHash is the RFC 6962-style interior node hash: SHA-256 of a 0x01 prefix followed by the
+// concatenation of the two child hashes.
+func interiorHash(left, right [32]byte) [32]byte {
+	h := sha256.New()
+	h.Write([]byte{0x01})
+	h.Write(left[:])
+	h.Write(right[:])
+
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+
+	return out
+}
+
+func encodeUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	for i := 7; i >= 0; i-- {
+		b[i] = byte(v)
+		v >>= 8
+	}
+
+	return b
+}
@@ -0,0 +1,124 @@
+//go:build boltdb
+
+package voprf
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var auditBucket = []byte("voprf-audit-log")
+
+// boltLogStore is a LogStore backed by a bolt database, for deployments that need the audit log to
+// survive a restart without standing up a separate database server.
+type boltLogStore struct {
+	db *bolt.DB
+}
+
+// NewBoltLogStore opens (creating if necessary) a bolt-backed audit LogStore at path.
+func NewBoltLogStore(path string) (LogStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("voprf: opening audit database: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(auditBucket)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("voprf: initializing audit database: %w", err)
+	}
+
+	return &boltLogStore{db: db}, nil
+}
+
+func (b *boltLogStore) Append(entry *auditEntry) (uint64, error) {
+	var index uint64
+
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(auditBucket)
+		index = uint64(bucket.Stats().KeyN)
+
+		return bucket.Put(encodeUint64(index), encodeAuditEntry(entry))
+	})
+
+	return index, err
+}
+
+func (b *boltLogStore) Get(index uint64) (*auditEntry, error) {
+	var entry *auditEntry
+
+	err := b.db.View(func(tx *bolt.Tx) error {
+		raw := tx.Bucket(auditBucket).Get(encodeUint64(index))
+		if raw == nil {
+			return fmt.Errorf("voprf: audit log has no entry at index %d", index)
+		}
+
+		var err error
+		entry, err = decodeAuditEntry(raw)
+
+		return err
+	})
+
+	return entry, err
+}
+
+func (b *boltLogStore) Size() uint64 {
+	var size uint64
+
+	_ = b.db.View(func(tx *bolt.Tx) error {
+		size = uint64(tx.Bucket(auditBucket).Stats().KeyN)
+		return nil
+	})
+
+	return size
+}
+
+// encodeAuditEntry serializes an auditEntry as timestamp || blindedHash || evaluatedHash ||
+// len(proofC) || proofC || proofS.
+func encodeAuditEntry(e *auditEntry) []byte {
+	out := encodeUint64(uint64(e.timestamp))
+	out = append(out, e.blindedHash[:]...)
+	out = append(out, e.evaluatedHash[:]...)
+	out = append(out, encodeUint64(uint64(len(e.proofC)))...)
+	out = append(out, e.proofC...)
+	out = append(out, e.proofS...)
+
+	return out
+}
+
+// decodeAuditEntry reverses encodeAuditEntry.
+func decodeAuditEntry(b []byte) (*auditEntry, error) {
+	if len(b) < 8+32+32+8 {
+		return nil, fmt.Errorf("voprf: truncated audit entry")
+	}
+
+	timestamp := int64(binary.BigEndian.Uint64(b[:8]))
+	b = b[8:]
+
+	var blindedHash, evaluatedHash [32]byte
+	copy(blindedHash[:], b[:32])
+	copy(evaluatedHash[:], b[32:64])
+	b = b[64:]
+
+	proofCLen := binary.BigEndian.Uint64(b[:8])
+	b = b[8:]
+
+	if uint64(len(b)) < proofCLen {
+		return nil, fmt.Errorf("voprf: truncated audit entry proof")
+	}
+
+	proofC := b[:proofCLen]
+	proofS := b[proofCLen:]
+
+	return &auditEntry{
+		timestamp:     timestamp,
+		blindedHash:   blindedHash,
+		evaluatedHash: evaluatedHash,
+		proofC:        proofC,
+		proofS:        proofS,
+	}, nil
+}
@@ -0,0 +1,119 @@
+package voprf
+
+import (
+	"testing"
+
+	"github.com/bytemare/voprf/internal/testgroup"
+)
+
+func TestVerifyInclusion(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Verifiable)
+	s.EnableAudit(nil)
+
+	c := NewClient(g, Verifiable, s.keyring.activeEpoch().publicKey)
+
+	inputs := [][]byte{[]byte("a"), []byte("b"), []byte("c")}
+	entries := make([]*AuditEntry, len(inputs))
+	proofs := make([]*InclusionProof, len(inputs))
+
+	for i, input := range inputs {
+		blinded, err := c.Blind(input)
+		if err != nil {
+			t.Fatalf("Blind %d: %v", i, err)
+		}
+
+		ev, err := s.Evaluate(blinded)
+		if err != nil {
+			t.Fatalf("Evaluate %d: %v", i, err)
+		}
+
+		if _, err := c.Finalize(ev, nil); err != nil {
+			t.Fatalf("Finalize %d: %v", i, err)
+		}
+
+		logged, err := s.auditLog.Get(uint64(i))
+		if err != nil {
+			t.Fatalf("reading back logged entry %d: %v", i, err)
+		}
+
+		entries[i] = &AuditEntry{
+			Timestamp: logged.timestamp,
+			Blinded:   [][]byte{blinded},
+			Evaluated: ev.Elements,
+			ProofC:    ev.ProofC,
+			ProofS:    ev.ProofS,
+		}
+	}
+
+	// Proofs are taken once the log holds every entry, so they are against the same tree size as
+	// the head published below.
+	for i := range inputs {
+		proof, err := s.LogProof(uint64(i))
+		if err != nil {
+			t.Fatalf("LogProof %d: %v", i, err)
+		}
+
+		proofs[i] = proof
+	}
+
+	head, err := s.PublishTreeHead()
+	if err != nil {
+		t.Fatalf("PublishTreeHead: %v", err)
+	}
+
+	for i := range inputs {
+		if !VerifyInclusion(head, entries[i], proofs[i]) {
+			t.Fatalf("entry %d failed to verify its own inclusion proof", i)
+		}
+	}
+
+	if VerifyInclusion(head, entries[0], proofs[1]) {
+		t.Fatal("VerifyInclusion accepted entry 0 against entry 1's proof")
+	}
+}
+
+func TestVerifyTreeHead(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Verifiable)
+	s.EnableAudit(nil)
+
+	c := NewClient(g, Verifiable, s.keyring.activeEpoch().publicKey)
+
+	blinded, err := c.Blind([]byte("input"))
+	if err != nil {
+		t.Fatalf("Blind: %v", err)
+	}
+
+	if _, err := s.Evaluate(blinded); err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	head, err := s.PublishTreeHead()
+	if err != nil {
+		t.Fatalf("PublishTreeHead: %v", err)
+	}
+
+	ok, err := VerifyTreeHead(g, s.PublicKey(), head)
+	if err != nil {
+		t.Fatalf("VerifyTreeHead: %v", err)
+	}
+
+	if !ok {
+		t.Fatal("VerifyTreeHead rejected a genuine tree head")
+	}
+
+	forged := &TreeHead{Size: head.Size, RootHash: head.RootHash, Signature: head.Signature}
+	forged.RootHash[0] ^= 0xff
+
+	ok, err = VerifyTreeHead(g, s.PublicKey(), forged)
+	if err != nil {
+		t.Fatalf("VerifyTreeHead: %v", err)
+	}
+
+	if ok {
+		t.Fatal("VerifyTreeHead accepted a forged root hash")
+	}
+}
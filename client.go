@@ -0,0 +1,98 @@
+package voprf
+
+import (
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// Client holds the (V)OPRF verifier data: the group to operate in, and the issuer public key(s)
+// needed to check an Evaluation's DLEQ proof in Verifiable mode. issuerKeys is only populated by
+// SetIssuerKeys, for servers that rotate keys across epochs; a client only expecting one key can
+// leave it nil and rely on publicKey alone.
+type Client struct {
+	publicKey  group.Element
+	issuerKeys map[uint32]group.Element
+	pending    *pendingEvaluation
+	*oprf
+}
+
+// pendingEvaluation holds the blinding state between Blind and Finalize.
+type pendingEvaluation struct {
+	input []byte
+	blind group.Scalar
+}
+
+// NewClient returns a Client operating over g and checking evaluations in mode against pk. pk may be
+// nil for Base mode, or for a Verifiable-mode client that will call SetIssuerKeys instead of relying
+// on a single fixed key.
+func NewClient(g group.Group, mode Mode, pk group.Element) *Client {
+	return &Client{
+		publicKey: pk,
+		oprf:      &oprf{group: g, mode: mode},
+	}
+}
+
+// Blind draws a fresh random blind, hashes input to the group, and blinds it, returning the wire
+// bytes to send to a Server's Evaluate. It must be followed by a single call to Finalize with the
+// resulting EpochEvaluation; a second Blind before that discards the pending state.
+func (c *Client) Blind(input []byte) ([]byte, error) {
+	blind := c.group.NewScalar().Random()
+	t := c.group.HashToGroup(input)
+	blinded := t.Mult(blind)
+
+	c.pending = &pendingEvaluation{input: input, blind: blind}
+
+	return blinded.Bytes(), nil
+}
+
+// Finalize checks ev's DLEQ proof, in Verifiable mode, against the issuer key for the epoch ev says
+// it was issued under - resolved via issuerKey, so a server that rotates keys is still checked
+// against the right one - removes the blinding factor Blind applied, and returns the same output
+// digest FullEvaluate/VerifyFinalize would compute server-side. It must be called after Blind
+// produced the input ev is an evaluation of.
+func (c *Client) Finalize(ev *EpochEvaluation, info []byte) ([]byte, error) {
+	if c.pending == nil {
+		return nil, fmt.Errorf("voprf: finalize called without a pending blind")
+	}
+
+	if len(ev.Elements) != 1 {
+		return nil, fmt.Errorf("voprf: finalize expects a single-element evaluation")
+	}
+
+	blindedElement := c.group.HashToGroup(c.pending.input).Mult(c.pending.blind)
+
+	evaluated, err := c.group.NewElement().Decode(ev.Elements[0])
+	if err != nil {
+		return nil, fmt.Errorf("voprf: decoding evaluated element: %w", err)
+	}
+
+	if c.mode == Verifiable {
+		pk, err := c.issuerKey(ev.EpochID)
+		if err != nil {
+			return nil, fmt.Errorf("voprf: finalize: %w", err)
+		}
+
+		proofC, err := c.group.NewScalar().Decode(ev.ProofC)
+		if err != nil {
+			return nil, fmt.Errorf("voprf: decoding proof challenge: %w", err)
+		}
+
+		proofS, err := c.group.NewScalar().Decode(ev.ProofS)
+		if err != nil {
+			return nil, fmt.Errorf("voprf: decoding proof response: %w", err)
+		}
+
+		extra := encodeUint64(uint64(ev.EpochID))
+		if !c.verifyProof(pk, []group.Element{blindedElement}, []group.Element{evaluated}, proofC, proofS, extra) {
+			return nil, fmt.Errorf("voprf: evaluation failed DLEQ verification")
+		}
+	}
+
+	t := evaluated.Mult(c.pending.blind.Invert())
+	digest := c.hashTranscript(c.pending.input, t.Bytes(), info)
+
+	c.pending = nil
+
+	return digest, nil
+}
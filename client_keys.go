@@ -0,0 +1,42 @@
+package voprf
+
+import (
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// SetIssuerKeys tells the client which public key to expect for each of a server's key epochs, so
+// Finalize can pick the right one for the epoch an EpochEvaluation says it was issued under, instead
+// of assuming the server only ever has one key.
+func (c *Client) SetIssuerKeys(keys map[uint32][]byte) error {
+	if c.issuerKeys == nil {
+		c.issuerKeys = make(map[uint32]group.Element, len(keys))
+	}
+
+	for id, raw := range keys {
+		pk, err := c.group.NewElement().Decode(raw)
+		if err != nil {
+			return fmt.Errorf("voprf: decoding issuer key for epoch %d: %w", id, err)
+		}
+
+		c.issuerKeys[id] = pk
+	}
+
+	return nil
+}
+
+// issuerKey returns the public key the client should verify against for the given epoch, falling
+// back to the client's single configured key if SetIssuerKeys was never called.
+func (c *Client) issuerKey(epochID uint32) (group.Element, error) {
+	if c.issuerKeys == nil {
+		return c.publicKey, nil
+	}
+
+	pk, ok := c.issuerKeys[epochID]
+	if !ok {
+		return nil, fmt.Errorf("voprf: no known issuer key for epoch %d", epochID)
+	}
+
+	return pk, nil
+}
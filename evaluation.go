@@ -0,0 +1,33 @@
+package voprf
+
+import "github.com/bytemare/cryptotools/hashtogroup/group"
+
+// evaluation is the in-progress, not-yet-serialized result of a Server evaluation.
+type evaluation struct {
+	elements       []group.Element
+	proofC, proofS group.Scalar
+}
+
+// Evaluation is the wire format of a Server evaluation: the evaluated element(s) and, in Verifiable
+// mode, the DLEQ proof attesting they were computed with the key behind the server's public key.
+type Evaluation struct {
+	Elements [][]byte
+	ProofC   []byte
+	ProofS   []byte
+}
+
+// serialize converts an in-progress evaluation into its wire format.
+func (e *evaluation) serialize() *Evaluation {
+	out := &Evaluation{Elements: make([][]byte, len(e.elements))}
+
+	for i, el := range e.elements {
+		out.Elements[i] = el.Bytes()
+	}
+
+	if e.proofC != nil {
+		out.ProofC = e.proofC.Bytes()
+		out.ProofS = e.proofS.Bytes()
+	}
+
+	return out
+}
@@ -0,0 +1,167 @@
+// Package testgroup is a small, self-contained implementation of group.Group for this module's own
+// tests: a prime-order subgroup of (Z/pZ)* built from the RFC 3526 2048-bit MODP safe prime, so
+// scalar arithmetic (in particular Invert, which Lagrange interpolation relies on) is always over a
+// field. It exists only so the protocol logic in this module can be exercised without the real
+// elliptic-curve ciphersuite this module ships against, which is not resolvable in this environment.
+package testgroup
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+	"math/big"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// p is the RFC 3526 2048-bit MODP group prime, a safe prime (p = 2q+1 with q prime).
+var p, _ = new(big.Int).SetString(""+
+	"FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B139B22514A08798E3404DD"+
+	"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED"+
+	"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3DC2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F"+
+	"83655D23DCA3AD961C62F356208552BB9ED529077096966D670C354E4ABC9804F1746C08CA18217C32905E462E36CE3B"+
+	"E39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9DE2BCBF6955817183995497CEA956AE515D2261898FA0510"+
+	"15728E5A8AACAA68FFFFFFFFFFFFFFFF", 16)
+
+// q is the order of the quadratic-residue subgroup of p: q = (p-1)/2, itself prime since p is safe.
+var q = new(big.Int).Rsh(new(big.Int).Sub(p, big.NewInt(1)), 1)
+
+// base generates the order-q subgroup: squaring 2 (RFC 3526's generator of the full order-2q group)
+// lands in the subgroup of quadratic residues, which has prime order q.
+var base = new(big.Int).Exp(big.NewInt(2), big.NewInt(2), p)
+
+const byteLen = 256 // ceil(2048/8); both p and q fit in this many bytes.
+
+// New returns a fresh testgroup.
+func New() group.Group { return groupImpl{} }
+
+type groupImpl struct{}
+
+func (groupImpl) NewScalar() group.Scalar { return &scalar{v: new(big.Int)} }
+
+func (groupImpl) NewElement() group.Element { return &element{v: new(big.Int)} }
+
+func (groupImpl) Base() group.Element { return &element{v: new(big.Int).Set(base)} }
+
+func (groupImpl) Identity() group.Element { return &element{v: big.NewInt(1)} }
+
+func (groupImpl) HashToGroup(data []byte) group.Element {
+	return &element{v: new(big.Int).Exp(base, hashToInt(data, q), p)}
+}
+
+func (groupImpl) HashToScalar(data []byte) group.Scalar {
+	return &scalar{v: hashToInt(data, q)}
+}
+
+// hashToInt derives a value in [0, mod) from data, by treating a wide hash as a big-endian integer
+// and reducing it.
+func hashToInt(data []byte, mod *big.Int) *big.Int {
+	h := sha512.Sum512(data)
+	return new(big.Int).Mod(new(big.Int).SetBytes(h[:]), mod)
+}
+
+type scalar struct{ v *big.Int }
+
+func (s *scalar) Random() group.Scalar {
+	v, err := rand.Int(rand.Reader, q)
+	if err != nil {
+		panic(fmt.Sprintf("testgroup: drawing random scalar: %v", err))
+	}
+
+	s.v = v
+
+	return s
+}
+
+func (s *scalar) Add(o group.Scalar) group.Scalar {
+	return &scalar{v: new(big.Int).Mod(new(big.Int).Add(s.v, o.(*scalar).v), q)}
+}
+
+func (s *scalar) Sub(o group.Scalar) group.Scalar {
+	return &scalar{v: new(big.Int).Mod(new(big.Int).Sub(s.v, o.(*scalar).v), q)}
+}
+
+func (s *scalar) Mult(o group.Scalar) group.Scalar {
+	return &scalar{v: new(big.Int).Mod(new(big.Int).Mul(s.v, o.(*scalar).v), q)}
+}
+
+func (s *scalar) Invert() group.Scalar {
+	return &scalar{v: new(big.Int).ModInverse(s.v, q)}
+}
+
+func (s *scalar) One() group.Scalar {
+	s.v = big.NewInt(1)
+	return s
+}
+
+func (s *scalar) Zero() group.Scalar {
+	s.v = big.NewInt(0)
+	return s
+}
+
+func (s *scalar) SetUInt64(n uint64) group.Scalar {
+	s.v = new(big.Int).SetUint64(n)
+	return s
+}
+
+func (s *scalar) Equal(o group.Scalar) bool {
+	return s.v.Cmp(o.(*scalar).v) == 0
+}
+
+func (s *scalar) Bytes() []byte {
+	out := make([]byte, byteLen)
+	s.v.FillBytes(out)
+
+	return out
+}
+
+func (s *scalar) Decode(b []byte) (group.Scalar, error) {
+	if len(b) != byteLen {
+		return nil, fmt.Errorf("testgroup: invalid scalar length %d", len(b))
+	}
+
+	v := new(big.Int).SetBytes(b)
+	if v.Cmp(q) >= 0 {
+		return nil, fmt.Errorf("testgroup: scalar out of range")
+	}
+
+	s.v = v
+
+	return s, nil
+}
+
+type element struct{ v *big.Int }
+
+func (e *element) Add(o group.Element) group.Element {
+	return &element{v: new(big.Int).Mod(new(big.Int).Mul(e.v, o.(*element).v), p)}
+}
+
+func (e *element) Mult(s group.Scalar) group.Element {
+	return &element{v: new(big.Int).Exp(e.v, s.(*scalar).v, p)}
+}
+
+func (e *element) Equal(o group.Element) bool {
+	return e.v.Cmp(o.(*element).v) == 0
+}
+
+func (e *element) Bytes() []byte {
+	out := make([]byte, byteLen)
+	e.v.FillBytes(out)
+
+	return out
+}
+
+func (e *element) Decode(b []byte) (group.Element, error) {
+	if len(b) != byteLen {
+		return nil, fmt.Errorf("testgroup: invalid element length %d", len(b))
+	}
+
+	v := new(big.Int).SetBytes(b)
+	if v.Cmp(p) >= 0 {
+		return nil, fmt.Errorf("testgroup: element out of range")
+	}
+
+	e.v = v
+
+	return e, nil
+}
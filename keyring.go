@@ -0,0 +1,191 @@
+package voprf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// epoch is one generation of the server's signing key, valid for a bounded window of time so a
+// rotated-out key can still verify in-flight clients without invalidating everything they hold.
+type epoch struct {
+	id         uint32
+	privateKey group.Scalar
+	publicKey  group.Element
+	notBefore  time.Time
+	notAfter   time.Time
+}
+
+// keyring holds every epoch the server still accepts, keyed by epoch ID, plus the ID of the epoch
+// new evaluations are issued under.
+type keyring struct {
+	epochs map[uint32]*epoch
+	active uint32
+}
+
+// EpochEvaluation pairs an Evaluation with the key epoch it was produced under, so a client holding
+// more than one issuer key can pick the one matching epochID before calling Finalize.
+type EpochEvaluation struct {
+	*Evaluation
+	EpochID uint32
+}
+
+func newKeyring() *keyring {
+	return &keyring{epochs: make(map[uint32]*epoch)}
+}
+
+// activeEpoch returns the epoch new evaluations are issued under.
+func (k *keyring) activeEpoch() *epoch {
+	return k.epochs[k.active]
+}
+
+// epoch looks up a specific epoch by ID, for verifying evaluations issued before the last rotation.
+func (k *keyring) epoch(id uint32) (*epoch, error) {
+	e, ok := k.epochs[id]
+	if !ok {
+		return nil, fmt.Errorf("voprf: unknown key epoch %d", id)
+	}
+
+	return e, nil
+}
+
+// RotateKey generates a fresh key epoch and makes it active. The previous active epoch, if any, is
+// kept in the ring until grace elapses, so Evaluations it already signed keep verifying for clients
+// that haven't picked up the new epoch yet.
+func (s *Server) RotateKey(grace time.Duration) {
+	if s.keyring == nil {
+		s.keyring = newKeyring()
+	}
+
+	next := nextEpochID(s.keyring)
+	now := time.Now()
+
+	if prev := s.keyring.activeEpoch(); prev != nil {
+		prev.notAfter = now.Add(grace)
+	}
+
+	e := &epoch{
+		id:         next,
+		privateKey: s.group.NewScalar().Random(),
+		notBefore:  now,
+	}
+	e.publicKey = s.group.Base().Mult(e.privateKey)
+
+	s.keyring.epochs[next] = e
+	s.keyring.active = next
+}
+
+// nextEpochID picks an unused epoch ID one past the highest currently held.
+func nextEpochID(k *keyring) uint32 {
+	var max uint32
+
+	for id := range k.epochs {
+		if id >= max {
+			max = id + 1
+		}
+	}
+
+	return max
+}
+
+// ExportKeyring serializes every epoch in the ring, sealed under an authenticated-encryption key, so
+// operators can persist it or share it across replicas. The epoch with expired notAfter are pruned
+// before export.
+func (s *Server) ExportKeyring(aeadKey []byte) ([]byte, error) {
+	s.keyring.prune(time.Now())
+
+	plaintext := make([]byte, 0)
+
+	for _, e := range s.keyring.epochs {
+		plaintext = append(plaintext, encodeEpoch(e)...)
+	}
+
+	return sealKeyring(aeadKey, plaintext)
+}
+
+// ImportKeyring decrypts and loads a keyring previously produced by ExportKeyring, replacing the
+// server's current ring and making activeID its active epoch.
+func (s *Server) ImportKeyring(aeadKey, sealed []byte, activeID uint32) error {
+	plaintext, err := openKeyring(aeadKey, sealed)
+	if err != nil {
+		return fmt.Errorf("voprf: importing keyring: %w", err)
+	}
+
+	k := newKeyring()
+
+	for len(plaintext) > 0 {
+		e, rest, err := decodeEpoch(s.group, plaintext)
+		if err != nil {
+			return fmt.Errorf("voprf: importing keyring: %w", err)
+		}
+
+		k.epochs[e.id] = e
+		plaintext = rest
+	}
+
+	if _, ok := k.epochs[activeID]; !ok {
+		return fmt.Errorf("voprf: imported keyring has no epoch %d", activeID)
+	}
+
+	k.active = activeID
+	s.keyring = k
+
+	return nil
+}
+
+// prune drops epochs whose grace window has elapsed.
+func (k *keyring) prune(now time.Time) {
+	for id, e := range k.epochs {
+		if id != k.active && !e.notAfter.IsZero() && now.After(e.notAfter) {
+			delete(k.epochs, id)
+		}
+	}
+}
+
+// encodeEpoch serializes one epoch as id || sk || notBefore (unix seconds) || notAfter (unix seconds).
+func encodeEpoch(e *epoch) []byte {
+	sk := e.privateKey.Bytes()
+
+	out := make([]byte, 4+len(sk)+8+8)
+	binary.BigEndian.PutUint32(out[0:4], e.id)
+	copy(out[4:], sk)
+	binary.BigEndian.PutUint64(out[4+len(sk):], uint64(e.notBefore.Unix()))
+	binary.BigEndian.PutUint64(out[12+len(sk):], uint64(e.notAfter.Unix()))
+
+	return out
+}
+
+// decodeEpoch reverses encodeEpoch, returning the decoded epoch and the unconsumed remainder.
+func decodeEpoch(g group.Group, b []byte) (*epoch, []byte, error) {
+	if len(b) < 4 {
+		return nil, nil, fmt.Errorf("truncated epoch header")
+	}
+
+	id := binary.BigEndian.Uint32(b[0:4])
+	b = b[4:]
+
+	sk := g.NewScalar()
+
+	skLen := len(sk.Bytes())
+	if len(b) < skLen+16 {
+		return nil, nil, fmt.Errorf("truncated epoch body")
+	}
+
+	sk, err := g.NewScalar().Decode(b[:skLen])
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding epoch key: %w", err)
+	}
+
+	notBefore := time.Unix(int64(binary.BigEndian.Uint64(b[skLen:skLen+8])), 0)
+	notAfter := time.Unix(int64(binary.BigEndian.Uint64(b[skLen+8:skLen+16])), 0)
+
+	return &epoch{
+		id:         id,
+		privateKey: sk,
+		publicKey:  g.Base().Mult(sk),
+		notBefore:  notBefore,
+		notAfter:   notAfter,
+	}, b[skLen+16:], nil
+}
@@ -0,0 +1,53 @@
+package voprf
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bytemare/voprf/internal/testgroup"
+)
+
+func TestExportImportKeyringRoundTrip(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Base)
+
+	aeadKey := bytes.Repeat([]byte{0x42}, 32)
+
+	sealed, err := s.ExportKeyring(aeadKey)
+	if err != nil {
+		t.Fatalf("ExportKeyring: %v", err)
+	}
+
+	imported := &Server{oprf: &oprf{group: g}}
+	if err := imported.ImportKeyring(aeadKey, sealed, s.ActiveEpoch()); err != nil {
+		t.Fatalf("ImportKeyring: %v", err)
+	}
+
+	if !bytes.Equal(imported.PrivateKey(), s.PrivateKey()) {
+		t.Fatal("imported private key does not match the exported one")
+	}
+
+	if !bytes.Equal(imported.PublicKey(), s.PublicKey()) {
+		t.Fatal("imported public key does not match the exported one")
+	}
+}
+
+func TestImportKeyringRejectsWrongKey(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Base)
+
+	aeadKey := bytes.Repeat([]byte{0x42}, 32)
+	wrongKey := bytes.Repeat([]byte{0x24}, 32)
+
+	sealed, err := s.ExportKeyring(aeadKey)
+	if err != nil {
+		t.Fatalf("ExportKeyring: %v", err)
+	}
+
+	imported := &Server{oprf: &oprf{group: g}}
+	if err := imported.ImportKeyring(wrongKey, sealed, s.ActiveEpoch()); err == nil {
+		t.Fatal("ImportKeyring accepted a keyring sealed under a different key")
+	}
+}
@@ -0,0 +1,107 @@
+package voprf
+
+import "crypto/sha256"
+
+// merkleRoot computes the RFC 6962-style root hash over leaves. An empty tree's root is the hash
+// of zero bytes, matching the empty-input convention of a SHA-256-based Merkle tree.
+func merkleRoot(leaves [][32]byte) [32]byte {
+	if len(leaves) == 0 {
+		return sha256Sum(nil)
+	}
+
+	return subtreeHash(leaves)
+}
+
+// subtreeHash recursively hashes leaves[0:k] and leaves[k:n] together, where k is the largest power
+// of two strictly smaller than n, per RFC 6962's definition of MTH for a non-power-of-two size.
+func subtreeHash(leaves [][32]byte) [32]byte {
+	if len(leaves) == 1 {
+		return leaves[0]
+	}
+
+	k := largestPowerOfTwoBelow(len(leaves))
+
+	return interiorHash(subtreeHash(leaves[:k]), subtreeHash(leaves[k:]))
+}
+
+// PathStep is one level of an inclusion proof's audit path: the sibling subtree's hash, and whether
+// it sits to the left or right of the node it combines with. RFC 6962's recursive, non-power-of-two
+// split means which side a sibling falls on can't be recovered from the leaf index alone (see
+// recomputeRoot), so the path carries it explicitly instead.
+type PathStep struct {
+	Sibling [32]byte
+	Left    bool
+}
+
+// merklePath returns the audit path for the leaf at index: the sibling hash and side at every level
+// needed to recompute the root from LeafHash alone.
+func merklePath(leaves [][32]byte, index uint64) []PathStep {
+	return pathFor(leaves, int(index))
+}
+
+func pathFor(leaves [][32]byte, index int) []PathStep {
+	if len(leaves) == 1 {
+		return nil
+	}
+
+	k := largestPowerOfTwoBelow(len(leaves))
+
+	if index < k {
+		return append(pathFor(leaves[:k], index), PathStep{Sibling: subtreeHash(leaves[k:]), Left: false})
+	}
+
+	return append(pathFor(leaves[k:], index-k), PathStep{Sibling: subtreeHash(leaves[:k]), Left: true})
+}
+
+// largestPowerOfTwoBelow returns the largest power of two strictly smaller than n, for n > 1.
+func largestPowerOfTwoBelow(n int) int {
+	k := 1
+	for k*2 < n {
+		k *= 2
+	}
+
+	return k
+}
+
+// VerifyInclusion checks that entry, at the index recorded in proof, is present under head. entry's
+// leaf hash is recomputed independently and required to match proof.LeafHash before it is folded
+// against proof.Path - a server cannot satisfy this by handing back a LeafHash belonging to some
+// other, genuinely-logged entry at that index, which trusting proof.LeafHash outright would allow.
+// Third parties use this to hold a server to evaluations it has published a tree head for, without
+// needing the full log.
+func VerifyInclusion(head *TreeHead, entry *AuditEntry, proof *InclusionProof) bool {
+	if proof.Index >= head.Size {
+		return false
+	}
+
+	computedLeaf := entry.leafHash()
+	if computedLeaf != proof.LeafHash {
+		return false
+	}
+
+	computed := recomputeRoot(computedLeaf, proof.Path)
+
+	return computed == head.RootHash
+}
+
+// recomputeRoot walks an RFC 6962 audit path bottom-up, combining the leaf with each sibling in
+// proof.Path in the order an inclusion proof lists them (innermost first), on the side each step
+// records. Unlike deriving the side from the leaf index, this matches pathFor's recursive
+// largest-power-of-two split exactly, including for tree sizes that aren't themselves a power of two.
+func recomputeRoot(leaf [32]byte, path []PathStep) [32]byte {
+	node := leaf
+
+	for _, step := range path {
+		if step.Left {
+			node = interiorHash(step.Sibling, node)
+		} else {
+			node = interiorHash(node, step.Sibling)
+		}
+	}
+
+	return node
+}
+
+func sha256Sum(b []byte) [32]byte {
+	return sha256.Sum256(b)
+}
@@ -0,0 +1,152 @@
+package voprf
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// Mode selects whether a Server additionally produces, and a Client checks, a DLEQ proof attesting
+// that an evaluation was computed with the key behind the server's public key.
+type Mode uint8
+
+const (
+	// Base runs the OPRF without any proof of correct evaluation.
+	Base Mode = iota
+	// Verifiable additionally produces (server-side) and checks (client-side) a DLEQ proof.
+	Verifiable
+)
+
+// oprf holds the ciphersuite state shared by Server and Client: which group to operate in, and
+// whether evaluations carry a DLEQ proof.
+type oprf struct {
+	group group.Group
+	mode  Mode
+}
+
+// computeComposites folds a batch of blinded/evaluated element pairs into the (a0, a1) = (Z, M) pair
+// that generateProof builds its DLEQ over: M is a Fiat-Shamir-weighted composite of the blinded
+// elements, and Z is the same composite of the evaluated elements. If sk is non-nil, Z is derived
+// directly as M^sk instead of recombining the evaluated elements a second time - a shortcut only the
+// party holding sk can take. Verifiers, who don't have sk, pass nil and get Z the expensive way.
+func (o *oprf) computeComposites(sk group.Scalar, pk group.Element, blindedElements, evaluatedElements []group.Element) (a0, a1 group.Element) {
+	weights := o.compositeWeights(pk, blindedElements, evaluatedElements)
+
+	m := o.group.Identity()
+	for i, b := range blindedElements {
+		m = m.Add(b.Mult(weights[i]))
+	}
+
+	if sk != nil {
+		return m.Mult(sk), m
+	}
+
+	z := o.group.Identity()
+	for i, e := range evaluatedElements {
+		z = z.Add(e.Mult(weights[i]))
+	}
+
+	return z, m
+}
+
+// compositeWeights derives one Fiat-Shamir weight per element from a transcript of the whole batch,
+// so a composite can't be manipulated by reordering or duplicating its elements.
+func (o *oprf) compositeWeights(pk group.Element, blindedElements, evaluatedElements []group.Element) []group.Scalar {
+	seed := append([]byte{}, pk.Bytes()...)
+
+	for i := range blindedElements {
+		seed = append(seed, blindedElements[i].Bytes()...)
+		seed = append(seed, evaluatedElements[i].Bytes()...)
+	}
+
+	weights := make([]group.Scalar, len(blindedElements))
+	for i := range blindedElements {
+		weights[i] = o.group.HashToScalar(append(append([]byte{}, seed...), encodeUint64(uint64(i))...))
+	}
+
+	return weights
+}
+
+// proofScalar derives the Fiat-Shamir challenge for a DLEQ proof from its transcript. extra, when
+// given, is folded in after the proof's own elements; generateProof uses it to bind a key epoch ID
+// into the challenge, so a proof issued under one epoch can't be replayed as if it came from another.
+func (o *oprf) proofScalar(pk, a0, a1, a2, a3 group.Element, extra ...[]byte) group.Scalar {
+	transcript := append(append(append(append(
+		pk.Bytes(), a0.Bytes()...), a1.Bytes()...), a2.Bytes()...), a3.Bytes()...)
+
+	for _, e := range extra {
+		transcript = append(transcript, e...)
+	}
+
+	return o.group.HashToScalar(transcript)
+}
+
+// verifyProof checks a DLEQ proof produced by generateProof for the same blinded/evaluated batch,
+// without needing the private key: it recomputes the (a0, a1) composite the verifier's way (folding
+// evaluatedElements directly, rather than deriving them via sk), reconstructs the prover's
+// commitments from (proofC, proofS), and checks they hash back to proofC. extra must match whatever
+// generateProof was given (e.g. an epoch ID) for the proof to verify.
+func (o *oprf) verifyProof(pk group.Element, blindedElements, evaluatedElements []group.Element, proofC, proofS group.Scalar, extra ...[]byte) bool {
+	a0, a1 := o.computeComposites(nil, pk, blindedElements, evaluatedElements)
+
+	a2 := o.group.Base().Mult(proofS).Add(pk.Mult(proofC))
+	a3 := a1.Mult(proofS).Add(a0.Mult(proofC))
+
+	return o.proofScalar(pk, a0, a1, a2, a3, extra...).Equal(proofC)
+}
+
+// hashTranscript derives the client-visible PRF output for input, binding in the evaluated element
+// and any application-specific info.
+func (o *oprf) hashTranscript(input, evaluated, info []byte) []byte {
+	transcript := append(append(append([]byte("voprf-finalize-v1"), input...), evaluated...), info...)
+	return o.group.HashToScalar(transcript).Bytes()
+}
+
+// ctEqual compares two byte slices in constant time.
+func ctEqual(a, b []byte) bool {
+	return subtle.ConstantTimeCompare(a, b) == 1
+}
+
+// verifyProof checks a DLEQ proof the same way generateProof produces one, for callers that only
+// hold a serialized evaluation and its issuer's public key rather than a Server (e.g.
+// VerifyEvaluation, which the tokens package calls, or AggregateAndVerify).
+func verifyProof(g group.Group, pk group.Element, blindedElements, evaluatedElements []group.Element, proofC, proofS group.Scalar, extra ...[]byte) bool {
+	o := &oprf{group: g}
+	return o.verifyProof(pk, blindedElements, evaluatedElements, proofC, proofS, extra...)
+}
+
+// VerifyEvaluation checks a single-element DLEQ proof against pk without needing a Server: it
+// decodes blinded, evaluated and the proof from their wire bytes and checks the same relation
+// generateProof proves server-side. extra must match whatever the server folded into the proof's
+// transcript (e.g. an epoch ID) for the proof to verify. Callers that only hold a serialized
+// evaluation and the issuer's public key (e.g. the tokens package) use this instead of reconstructing
+// a Server.
+func VerifyEvaluation(g group.Group, pk, blinded, evaluated, proofC, proofS []byte, extra ...[]byte) (bool, error) {
+	pkElement, err := g.NewElement().Decode(pk)
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding public key: %w", err)
+	}
+
+	b, err := g.NewElement().Decode(blinded)
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding blinded element: %w", err)
+	}
+
+	e, err := g.NewElement().Decode(evaluated)
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding evaluated element: %w", err)
+	}
+
+	c, err := g.NewScalar().Decode(proofC)
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding proof challenge: %w", err)
+	}
+
+	s, err := g.NewScalar().Decode(proofS)
+	if err != nil {
+		return false, fmt.Errorf("voprf: decoding proof response: %w", err)
+	}
+
+	return verifyProof(g, pkElement, []group.Element{b}, []group.Element{e}, c, s, extra...), nil
+}
@@ -0,0 +1,55 @@
+package voprf
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
+)
+
+// sealKeyring encrypts plaintext under aeadKey with AES-GCM, prefixing the nonce, so an exported
+// keyring is unreadable to anyone who doesn't hold the export key.
+func sealKeyring(aeadKey, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(aeadKey)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("voprf: generating export nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// openKeyring reverses sealKeyring.
+func openKeyring(aeadKey, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(aeadKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("voprf: sealed keyring truncated")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("voprf: decrypting keyring: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newGCM(aeadKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(aeadKey)
+	if err != nil {
+		return nil, fmt.Errorf("voprf: invalid export key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -2,43 +2,104 @@ package voprf
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/bytemare/cryptotools/hashtogroup/group"
 )
 
-// Server holds the (V)OPRF prover data.
+// Server holds the (V)OPRF prover data, keyed by epoch so a compromised or aging key can be rotated
+// out without invalidating evaluations issued under earlier epochs.
 type Server struct {
-	privateKey group.Scalar
-	publicKey  group.Element
+	keyring  *keyring
+	auditLog LogStore
 	*oprf
 }
 
-func (s *Server) evaluate(blinded group.Element) group.Element {
-	return blinded.Mult(s.privateKey)
+// NewServer returns a Server operating over g in mode, with a freshly generated epoch-0 key (see
+// KeyGen). Construct a Server differently (e.g. via ImportKeyring) when provisioning existing key
+// material instead of generating new.
+func NewServer(g group.Group, mode Mode) *Server {
+	s := &Server{oprf: &oprf{group: g, mode: mode}}
+	s.KeyGen()
+
+	return s
+}
+
+func (s *Server) evaluate(blinded group.Element, e *epoch) group.Element {
+	return blinded.Mult(e.privateKey)
 }
 
-func (s *Server) generateProof(blindedElements, evaluatedElements []group.Element) (proofC, proofS group.Scalar) {
-	a0, a1 := s.computeComposites(s.privateKey, s.publicKey, blindedElements, evaluatedElements)
+func (s *Server) generateProof(e *epoch, blindedElements, evaluatedElements []group.Element) (proofC, proofS group.Scalar) {
+	a0, a1 := s.computeComposites(e.privateKey, e.publicKey, blindedElements, evaluatedElements)
 
 	r := s.group.NewScalar().Random()
 	a2 := s.group.Base().Mult(r)
 	a3 := a1.Mult(r)
 
-	proofC = s.proofScalar(s.publicKey, a0, a1, a2, a3)
-	m := proofC.Mult(s.privateKey)
+	proofC = s.proofScalar(e.publicKey, a0, a1, a2, a3, encodeUint64(uint64(e.id)))
+	m := proofC.Mult(e.privateKey)
 	proofS = r.Sub(m)
 
 	return proofC, proofS
 }
 
-// KeyGen generates and sets a new private/public key pair.
+// KeyGen generates and sets a new private/public key pair as epoch 0, discarding any existing
+// keyring. Use RotateKey instead once a server is already serving clients.
 func (s *Server) KeyGen() {
-	s.privateKey = s.group.NewScalar().Random()
-	s.publicKey = s.group.Base().Mult(s.privateKey)
+	s.keyring = newKeyring()
+
+	e := &epoch{
+		id:         0,
+		privateKey: s.group.NewScalar().Random(),
+	}
+	e.publicKey = s.group.Base().Mult(e.privateKey)
+
+	s.keyring.epochs[0] = e
+	s.keyring.active = 0
+}
+
+// resolveEpoch returns the epoch new evaluations should be issued under: the one explicitly
+// selected, or the keyring's active epoch if none was given. An epoch outside its notBefore/notAfter
+// validity window is rejected even if still present in the ring, so a rotated-out key only keeps
+// working for the grace period RotateKey gave it, not indefinitely.
+func (s *Server) resolveEpoch(selector []uint32) (*epoch, error) {
+	var e *epoch
+
+	if len(selector) == 0 {
+		e = s.keyring.activeEpoch()
+		if e == nil {
+			return nil, fmt.Errorf("voprf: server has no active key epoch")
+		}
+	} else {
+		var err error
+
+		e, err = s.keyring.epoch(selector[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+
+	if !e.notBefore.IsZero() && now.Before(e.notBefore) {
+		return nil, fmt.Errorf("voprf: key epoch %d is not yet valid", e.id)
+	}
+
+	if !e.notAfter.IsZero() && now.After(e.notAfter) {
+		return nil, fmt.Errorf("voprf: key epoch %d has expired", e.id)
+	}
+
+	return e, nil
 }
 
-// Evaluate the input with the private key.
-func (s *Server) Evaluate(blindedElement []byte) (*Evaluation, error) {
+// Evaluate the input with the private key. By default the active key epoch is used; pass an epoch
+// ID to evaluate against a specific (e.g. not-yet-rotated-out) one.
+func (s *Server) Evaluate(blindedElement []byte, epochID ...uint32) (*EpochEvaluation, error) {
+	e, err := s.resolveEpoch(epochID)
+	if err != nil {
+		return nil, err
+	}
+
 	ev := &evaluation{}
 	ev.elements = make([]group.Element, 1)
 
@@ -47,20 +108,28 @@ func (s *Server) Evaluate(blindedElement []byte) (*Evaluation, error) {
 		return nil, fmt.Errorf("OPRF can't evaluate input : %w", err)
 	}
 
-	ev.elements[0] = s.evaluate(b)
+	ev.elements[0] = s.evaluate(b, e)
 
 	if s.mode == Verifiable {
-		c, s := s.generateProof([]group.Element{b}, ev.elements)
+		c, ps := s.generateProof(e, []group.Element{b}, ev.elements)
 		ev.proofC = c
-		ev.proofS = s
+		ev.proofS = ps
+
+		s.recordAudit(time.Now().Unix(), []group.Element{b}, ev.elements, c, ps)
 	}
 
-	return ev.serialize(), nil
+	return &EpochEvaluation{Evaluation: ev.serialize(), EpochID: e.id}, nil
 }
 
 // EvaluateBatch evaluates the input batch of blindedElements and returns a pointer to the Evaluation. If the server
-// was set to be un Verifiable mode, the proof will be included in the Evaluation.
-func (s *Server) EvaluateBatch(blindedElements [][]byte) (*Evaluation, error) {
+// was set to be un Verifiable mode, the proof will be included in the Evaluation. By default the active key epoch
+// is used; pass an epoch ID to evaluate against a specific one.
+func (s *Server) EvaluateBatch(blindedElements [][]byte, epochID ...uint32) (*EpochEvaluation, error) {
+	e, err := s.resolveEpoch(epochID)
+	if err != nil {
+		return nil, err
+	}
+
 	ev := &evaluation{}
 	ev.elements = make([]group.Element, len(blindedElements))
 
@@ -80,27 +149,48 @@ func (s *Server) EvaluateBatch(blindedElements [][]byte) (*Evaluation, error) {
 			blinded[i] = b
 		}
 
-		ev.elements[i] = s.evaluate(b)
+		ev.elements[i] = s.evaluate(b, e)
 	}
 
 	if s.mode == Verifiable {
-		c, s := s.generateProof(blinded, ev.elements)
+		c, ps := s.generateProof(e, blinded, ev.elements)
 		ev.proofC = c
-		ev.proofS = s
+		ev.proofS = ps
+
+		s.recordAudit(time.Now().Unix(), blinded, ev.elements, c, ps)
 	}
 
-	return ev.serialize(), nil
+	return &EpochEvaluation{Evaluation: ev.serialize(), EpochID: e.id}, nil
 }
 
 // FullEvaluate reproduces the full PRF but without the blinding operations, using the client's input.
-// This should output the same digest as the client's Finalize() function.
+// This should output the same digest as the client's Finalize() function. It always uses the active
+// key epoch.
 func (s *Server) FullEvaluate(input, info []byte) []byte {
 	p := s.group.HashToGroup(input)
-	t := s.evaluate(p)
+	t := s.evaluate(p, s.keyring.activeEpoch())
 
 	return s.hashTranscript(input, t.Bytes(), info)
 }
 
+// EvaluatePRF evaluates the full (unblinded) PRF for input and returns the raw masked element
+// H(input)^sk, without the output transcript hash that FullEvaluate applies. Higher-level protocols
+// that need a per-input shared secret derived from the server's key (e.g. anonymous tokens) can use
+// this directly instead of re-deriving it from FullEvaluate's digest. By default the active key
+// epoch is used; pass an epoch ID to re-derive the secret under a specific (e.g. not-yet-rotated-out)
+// one, the same way Evaluate and EvaluateBatch let a caller pin an epoch.
+func (s *Server) EvaluatePRF(input []byte, epochID ...uint32) ([]byte, error) {
+	e, err := s.resolveEpoch(epochID)
+	if err != nil {
+		return nil, err
+	}
+
+	p := s.group.HashToGroup(input)
+	t := s.evaluate(p, e)
+
+	return t.Bytes(), nil
+}
+
 // VerifyFinalize takes the client input (the un-blinded element) and the client's finalize() output,
 // and returns whether it can match the client's output.
 func (s *Server) VerifyFinalize(input, output, info []byte) bool {
@@ -121,12 +211,17 @@ func (s *Server) VerifyFinalizeBatch(input, output [][]byte, info []byte) bool {
 	return res
 }
 
-// PrivateKey returns the server's serialized private key.
+// PrivateKey returns the serialized private key of the server's active epoch.
 func (s *Server) PrivateKey() []byte {
-	return s.privateKey.Bytes()
+	return s.keyring.activeEpoch().privateKey.Bytes()
 }
 
-// PublicKey returns the server's serialized public key.
+// PublicKey returns the serialized public key of the server's active epoch.
 func (s *Server) PublicKey() []byte {
-	return s.publicKey.Bytes()
+	return s.keyring.activeEpoch().publicKey.Bytes()
+}
+
+// ActiveEpoch returns the ID of the key epoch new evaluations are currently issued under.
+func (s *Server) ActiveEpoch() uint32 {
+	return s.keyring.active
 }
@@ -0,0 +1,147 @@
+package threshold
+
+import (
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// CombinedEvaluation is a threshold evaluation interpolated down to a single point, together with a
+// DLEQ proof over the aggregate statement (PubKey, blinded input, Element). ProofC is a challenge
+// over this package's own transcript (see dleqChallenge), not the transcript voprf.Client.Finalize
+// recomputes, and a CombinedEvaluation carries no key epoch ID, so its proof does not verify through
+// Finalize; this package has no function that verifies it either. See
+// JointChallenge and Combine for how ProofC/ProofS are derived soundly, from a two-round protocol
+// where every participant answers the same challenge, rather than by combining proofs that were
+// each already finalized against their own participant-specific challenge.
+type CombinedEvaluation struct {
+	Element []byte
+	PubKey  []byte
+	ProofC  []byte
+	ProofS  []byte
+}
+
+// JointChallenge derives the single Fiat-Shamir challenge every participant must answer via Respond,
+// from the Lagrange-weighted combination of their PartialCommitments. A combiner calls this once it
+// has collected commitments from at least t participants, before asking any of them to respond:
+// because every participant answers this same challenge, their responses can later be combined
+// (Combine) the same way their commitments were combined here, into one proof that is sound against
+// the aggregate statement (pk_agg, x, y_agg).
+func JointChallenge(g group.Group, blinded []byte, pks map[int]group.Element, commitments []*PartialCommitment) (group.Scalar, error) {
+	x, err := g.NewElement().Decode(blinded)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: decoding blinded input: %w", err)
+	}
+
+	coeffs := lagrangeCoefficients(g, commitmentIndices(commitments))
+
+	pkAgg := g.Identity()
+	yAgg := g.Identity()
+	a0Agg := g.Identity()
+	a1Agg := g.Identity()
+
+	for _, cm := range commitments {
+		pk, ok := pks[cm.Index]
+		if !ok {
+			return nil, fmt.Errorf("threshold: no verification key for participant %d", cm.Index)
+		}
+
+		lambda := coeffs[cm.Index]
+		pkAgg = pkAgg.Add(pk.Mult(lambda))
+		yAgg = yAgg.Add(cm.Y.Mult(lambda))
+		a0Agg = a0Agg.Add(cm.A0.Mult(lambda))
+		a1Agg = a1Agg.Add(cm.A1.Mult(lambda))
+	}
+
+	return dleqChallenge(g, g.Base(), pkAgg, x, yAgg, a0Agg, a1Agg), nil
+}
+
+// Combine checks every participant's PartialResponse against their own commitment and verification
+// key under challenge (the value JointChallenge derived from the same commitments), then
+// Lagrange-interpolates the verification keys, result shares and responses into a single
+// CombinedEvaluation. responses must cover exactly the participants in commitments - the set
+// JointChallenge derived its Lagrange coefficients over - or the combined proof would not match the
+// challenge a client will recompute.
+func Combine(g group.Group, blinded []byte, pks map[int]group.Element, commitments []*PartialCommitment, challenge group.Scalar, responses []*PartialResponse) (*CombinedEvaluation, error) {
+	x, err := g.NewElement().Decode(blinded)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: decoding blinded input: %w", err)
+	}
+
+	if len(responses) != len(commitments) {
+		return nil, fmt.Errorf("threshold: expected %d responses, got %d", len(commitments), len(responses))
+	}
+
+	byIndex := make(map[int]*PartialCommitment, len(commitments))
+	for _, cm := range commitments {
+		byIndex[cm.Index] = cm
+	}
+
+	coeffs := lagrangeCoefficients(g, commitmentIndices(commitments))
+
+	yAgg := g.Identity()
+	pkAgg := g.Identity()
+	sAgg := g.NewScalar().Zero()
+
+	for _, resp := range responses {
+		cm, ok := byIndex[resp.Index]
+		if !ok {
+			return nil, fmt.Errorf("threshold: response from participant %d has no matching commitment", resp.Index)
+		}
+
+		pk, ok := pks[resp.Index]
+		if !ok {
+			return nil, fmt.Errorf("threshold: no verification key for participant %d", resp.Index)
+		}
+
+		if !verifyResponse(g, pk, x, cm, challenge, resp) {
+			return nil, fmt.Errorf("threshold: invalid response from participant %d", resp.Index)
+		}
+
+		lambda := coeffs[resp.Index]
+		yAgg = yAgg.Add(cm.Y.Mult(lambda))
+		pkAgg = pkAgg.Add(pk.Mult(lambda))
+		sAgg = sAgg.Add(resp.S.Mult(lambda))
+	}
+
+	return &CombinedEvaluation{
+		Element: yAgg.Bytes(),
+		PubKey:  pkAgg.Bytes(),
+		ProofC:  challenge.Bytes(),
+		ProofS:  sAgg.Bytes(),
+	}, nil
+}
+
+// commitmentIndices extracts the participant index set of a batch of commitments.
+func commitmentIndices(commitments []*PartialCommitment) []int {
+	out := make([]int, len(commitments))
+	for i, cm := range commitments {
+		out[i] = cm.Index
+	}
+
+	return out
+}
+
+// lagrangeCoefficients computes, for each index in set, the Lagrange coefficient lambda_i that
+// interpolates a degree-(|set|-1) polynomial at x=0 from its values at the points in set.
+func lagrangeCoefficients(g group.Group, set []int) map[int]group.Scalar {
+	coeffs := make(map[int]group.Scalar, len(set))
+
+	for _, i := range set {
+		num := g.NewScalar().One()
+		den := g.NewScalar().One()
+
+		for _, j := range set {
+			if i == j {
+				continue
+			}
+
+			num = num.Mult(scalarFromInt(g, j))
+			den = den.Mult(scalarFromInt(g, j).Sub(scalarFromInt(g, i)))
+		}
+
+		coeffs[i] = num.Mult(den.Invert())
+	}
+
+	return coeffs
+}
@@ -0,0 +1,96 @@
+// Package threshold splits a VOPRF private key across n servers with threshold t, so that
+// evaluating the PRF requires cooperation of at least t shares and no single server ever learns sk.
+package threshold
+
+import (
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// VSSCommitment is a Feldman VSS commitment to a dealer's polynomial coefficients, published so
+// every participant can verify its share without trusting the dealer.
+type VSSCommitment []group.Element
+
+// GenerateShares runs a dealerless-trust Feldman VSS: it samples a degree t-1 polynomial with a
+// random constant term, returning the public commitments to its coefficients and, for each of the n
+// participant indices 1..n, the share of the polynomial evaluated at that index.
+func GenerateShares(g group.Group, t, n int) (commitment VSSCommitment, shares []group.Scalar, err error) {
+	if t < 1 || t > n {
+		return nil, nil, fmt.Errorf("threshold: invalid threshold %d for %d participants", t, n)
+	}
+
+	coeffs := make([]group.Scalar, t)
+	commitment = make(VSSCommitment, t)
+
+	for j := 0; j < t; j++ {
+		coeffs[j] = g.NewScalar().Random()
+		commitment[j] = g.Base().Mult(coeffs[j])
+	}
+
+	shares = make([]group.Scalar, n)
+	for i := 1; i <= n; i++ {
+		shares[i-1] = evalPolynomial(g, coeffs, i)
+	}
+
+	return commitment, shares, nil
+}
+
+// VerifyShare checks a participant's share s_i, received at index i, against the dealer's published
+// commitment: s_i * G must equal the sum of commitment[j] * i^j.
+func VerifyShare(g group.Group, commitment VSSCommitment, index int, share group.Scalar) bool {
+	lhs := g.Base().Mult(share)
+
+	rhs := commitment[0]
+	power := g.NewScalar().One()
+	idx := scalarFromInt(g, index)
+
+	for j := 1; j < len(commitment); j++ {
+		power = power.Mult(idx)
+		rhs = rhs.Add(commitment[j].Mult(power))
+	}
+
+	return lhs.Equal(rhs)
+}
+
+// evalPolynomial evaluates sum(coeffs[j] * x^j) at x = index over the scalar field.
+func evalPolynomial(g group.Group, coeffs []group.Scalar, index int) group.Scalar {
+	x := scalarFromInt(g, index)
+
+	result := coeffs[0]
+	power := g.NewScalar().One()
+
+	for j := 1; j < len(coeffs); j++ {
+		power = power.Mult(x)
+		result = result.Add(coeffs[j].Mult(power))
+	}
+
+	return result
+}
+
+// scalarFromInt encodes a small participant index as a scalar.
+func scalarFromInt(g group.Group, i int) group.Scalar {
+	return g.NewScalar().SetUInt64(uint64(i))
+}
+
+// CombineDealerShares sums the shares a participant received from every dealer in a dealerless DKG
+// round into that participant's final signing share sk_i.
+func CombineDealerShares(g group.Group, shares []group.Scalar) group.Scalar {
+	sk := g.NewScalar().Zero()
+	for _, s := range shares {
+		sk = sk.Add(s)
+	}
+
+	return sk
+}
+
+// CombineCommitments sums the constant-term commitments published by every dealer into the
+// aggregate public key pk = g^sk, without any party ever learning sk itself.
+func CombineCommitments(g group.Group, constantTerms []group.Element) group.Element {
+	pk := g.Identity()
+	for _, c := range constantTerms {
+		pk = pk.Add(c)
+	}
+
+	return pk
+}
@@ -0,0 +1,111 @@
+package threshold
+
+import (
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+)
+
+// PartialServer holds one participant's share sk_i of a threshold-split VOPRF private key, plus the
+// participant's published verification key pk_i = g^sk_i.
+type PartialServer struct {
+	g     group.Group
+	index int
+	share group.Scalar
+	pk    group.Element
+	nonce group.Scalar
+}
+
+// NewPartialServer returns a PartialServer for participant index (1-based) holding share as its key
+// share. The verification key pk_i = g^share is derived and published alongside partial evaluations.
+func NewPartialServer(g group.Group, index int, share group.Scalar) *PartialServer {
+	return &PartialServer{g: g, index: index, share: share, pk: g.Base().Mult(share)}
+}
+
+// Index returns this participant's 1-based share index, used to build Lagrange coefficients.
+func (p *PartialServer) Index() int {
+	return p.index
+}
+
+// VerificationKey returns pk_i = g^sk_i, published so peers and combiners can verify this
+// participant's partial evaluations without learning sk_i.
+func (p *PartialServer) VerificationKey() group.Element {
+	return p.pk
+}
+
+// PartialCommitment is a participant's first-round message in a threshold evaluation: its result
+// share Y = x^sk_i, and a Schnorr commitment (A0, A1) = (g^r, x^r) to randomness it has not yet
+// responded to. A combiner collects commitments from at least t participants and derives a single
+// joint challenge (see JointChallenge) from their Lagrange-weighted combination before asking any
+// participant to Respond - that shared challenge is what lets the eventual responses be combined
+// into one sound proof of the aggregate statement, unlike finalizing each participant's own DLEQ
+// proof against its own challenge and combining the finished proofs afterwards.
+type PartialCommitment struct {
+	Index int
+	Y     group.Element
+	A0    group.Element
+	A1    group.Element
+}
+
+// PartialResponse is a participant's second-round message: its Schnorr response to the joint
+// challenge a combiner derived from every participant's PartialCommitment.
+type PartialResponse struct {
+	Index int
+	S     group.Scalar
+}
+
+// Commit evaluates the blinded input under this participant's key share and produces a Schnorr
+// commitment to a freshly drawn nonce, without responding to any challenge yet. It must be followed
+// by exactly one call to Respond, once a combiner has derived a joint challenge from this and every
+// other participating server's commitment; a second Commit before that discards the pending nonce.
+func (p *PartialServer) Commit(blinded []byte) (*PartialCommitment, error) {
+	x, err := p.g.NewElement().Decode(blinded)
+	if err != nil {
+		return nil, fmt.Errorf("threshold: decoding blinded input: %w", err)
+	}
+
+	y := x.Mult(p.share)
+
+	r := p.g.NewScalar().Random()
+	p.nonce = r
+
+	return &PartialCommitment{
+		Index: p.index,
+		Y:     y,
+		A0:    p.g.Base().Mult(r),
+		A1:    x.Mult(r),
+	}, nil
+}
+
+// Respond computes this participant's Schnorr response to challenge - the joint challenge a combiner
+// derived from every participant's Commit output - and clears the pending nonce Commit drew.
+func (p *PartialServer) Respond(challenge group.Scalar) (*PartialResponse, error) {
+	if p.nonce == nil {
+		return nil, fmt.Errorf("threshold: respond called without a pending commitment")
+	}
+
+	s := p.nonce.Sub(challenge.Mult(p.share))
+	p.nonce = nil
+
+	return &PartialResponse{Index: p.index, S: s}, nil
+}
+
+// verifyResponse checks that resp is internally consistent with commitment under the shared
+// challenge: that the response a combiner was given really does reopen that participant's own
+// commitment, i.e. g^s*pk^c == A0 and x^s*Y^c == A1. Unlike a self-contained DLEQ check, this
+// doesn't re-derive challenge from a hash - challenge is already a value every participant is known
+// to have answered identically, fixed once by JointChallenge.
+func verifyResponse(g group.Group, pk, x group.Element, commitment *PartialCommitment, challenge group.Scalar, resp *PartialResponse) bool {
+	a0 := g.Base().Mult(resp.S).Add(pk.Mult(challenge))
+	a1 := x.Mult(resp.S).Add(commitment.Y.Mult(challenge))
+
+	return a0.Equal(commitment.A0) && a1.Equal(commitment.A1)
+}
+
+// dleqChallenge derives a Fiat-Shamir challenge scalar from a Chaum-Pedersen transcript.
+func dleqChallenge(g group.Group, base, pk, x, y, a0, a1 group.Element) group.Scalar {
+	transcript := append(append(append(append(append(
+		base.Bytes(), pk.Bytes()...), x.Bytes()...), y.Bytes()...), a0.Bytes()...), a1.Bytes()...)
+
+	return g.HashToScalar(transcript)
+}
@@ -0,0 +1,125 @@
+package threshold
+
+import (
+	"testing"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+	"github.com/bytemare/voprf/internal/testgroup"
+)
+
+func TestGenerateSharesVerifyCombineRoundTrip(t *testing.T) {
+	g := testgroup.New()
+
+	const threshold, participants = 2, 3
+
+	commitment, shares, err := GenerateShares(g, threshold, participants)
+	if err != nil {
+		t.Fatalf("GenerateShares: %v", err)
+	}
+
+	servers := make([]*PartialServer, participants)
+	pks := make(map[int]group.Element, participants)
+
+	for i := 0; i < participants; i++ {
+		index := i + 1
+
+		if !VerifyShare(g, commitment, index, shares[i]) {
+			t.Fatalf("share %d failed VSS verification", index)
+		}
+
+		servers[i] = NewPartialServer(g, index, shares[i])
+		pks[index] = servers[i].VerificationKey()
+	}
+
+	blinded := g.HashToGroup([]byte("input")).Bytes()
+
+	quorum := servers[:threshold]
+
+	commitments := make([]*PartialCommitment, len(quorum))
+	for i, srv := range quorum {
+		cm, err := srv.Commit(blinded)
+		if err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+
+		commitments[i] = cm
+	}
+
+	challenge, err := JointChallenge(g, blinded, pks, commitments)
+	if err != nil {
+		t.Fatalf("JointChallenge: %v", err)
+	}
+
+	responses := make([]*PartialResponse, len(quorum))
+	for i, srv := range quorum {
+		resp, err := srv.Respond(challenge)
+		if err != nil {
+			t.Fatalf("Respond: %v", err)
+		}
+
+		responses[i] = resp
+	}
+
+	combined, err := Combine(g, blinded, pks, commitments, challenge, responses)
+	if err != nil {
+		t.Fatalf("Combine: %v", err)
+	}
+
+	if len(combined.Element) == 0 || len(combined.PubKey) == 0 {
+		t.Fatal("Combine returned an empty evaluation")
+	}
+}
+
+func TestCombineRejectsBadResponse(t *testing.T) {
+	g := testgroup.New()
+
+	const threshold, participants = 2, 2
+
+	_, shares, err := GenerateShares(g, threshold, participants)
+	if err != nil {
+		t.Fatalf("GenerateShares: %v", err)
+	}
+
+	servers := make([]*PartialServer, participants)
+	pks := make(map[int]group.Element, participants)
+
+	for i := 0; i < participants; i++ {
+		index := i + 1
+		servers[i] = NewPartialServer(g, index, shares[i])
+		pks[index] = servers[i].VerificationKey()
+	}
+
+	blinded := g.HashToGroup([]byte("input")).Bytes()
+
+	commitments := make([]*PartialCommitment, participants)
+	for i, srv := range servers {
+		cm, err := srv.Commit(blinded)
+		if err != nil {
+			t.Fatalf("Commit: %v", err)
+		}
+
+		commitments[i] = cm
+	}
+
+	challenge, err := JointChallenge(g, blinded, pks, commitments)
+	if err != nil {
+		t.Fatalf("JointChallenge: %v", err)
+	}
+
+	responses := make([]*PartialResponse, participants)
+	for i, srv := range servers {
+		resp, err := srv.Respond(challenge)
+		if err != nil {
+			t.Fatalf("Respond: %v", err)
+		}
+
+		responses[i] = resp
+	}
+
+	// Corrupt one participant's response.
+	responses[0] = &PartialResponse{Index: responses[0].Index, S: responses[0].S.Add(g.NewScalar().One())}
+
+	if _, err := Combine(g, blinded, pks, commitments, challenge, responses); err == nil {
+		t.Fatal("Combine accepted a corrupted partial response")
+	}
+}
@@ -0,0 +1,107 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/bytemare/cryptotools/hashtogroup/group"
+	"github.com/bytemare/voprf"
+)
+
+// pendingToken holds the blinding state between GenBlindedToken and Unblind.
+type pendingToken struct {
+	nonce []byte
+	blind group.Scalar
+}
+
+// redeemableToken is a token that has been unblinded and is ready to be spent.
+type redeemableToken struct {
+	nonce   []byte
+	key     []byte
+	epochID uint32
+}
+
+// Client generates and spends anonymous tokens against a server sharing the same OPRF key.
+type Client struct {
+	g       group.Group
+	pending *pendingToken
+	token   *redeemableToken
+}
+
+// NewClient returns a token Client operating over the given group, which must match the issuing
+// server's ciphersuite.
+func NewClient(g group.Group) *Client {
+	return &Client{g: g}
+}
+
+// GenBlindedToken draws a fresh random nonce t, hashes it to the group, and blinds it, returning the
+// wire bytes to send to a server's IssueToken.
+func (c *Client) GenBlindedToken() ([]byte, error) {
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("tokens: generating nonce: %w", err)
+	}
+
+	blind := c.g.NewScalar().Random()
+	t := c.g.HashToGroup(nonce)
+	blinded := t.Mult(blind)
+
+	c.pending = &pendingToken{nonce: nonce, blind: blind}
+
+	return blinded.Bytes(), nil
+}
+
+// Unblind checks signed's DLEQ proof against issuerPK - the point of running the issuer in
+// Verifiable mode - and, only if it holds, removes the blinding factor to yield a token ready to
+// spend. It must be called after GenBlindedToken produced the token being unblinded.
+func (c *Client) Unblind(signed *SignedToken, issuerPK []byte) error {
+	if c.pending == nil {
+		return fmt.Errorf("tokens: unblind called without a pending token")
+	}
+
+	blindedToken := c.g.HashToGroup(c.pending.nonce).Mult(c.pending.blind).Bytes()
+
+	epochID := make([]byte, 8)
+	binary.BigEndian.PutUint64(epochID, uint64(signed.EpochID))
+
+	ok, err := voprf.VerifyEvaluation(c.g, issuerPK, blindedToken, signed.Element, signed.ProofC, signed.ProofS, epochID)
+	if err != nil {
+		return fmt.Errorf("tokens: checking signed token proof: %w", err)
+	}
+
+	if !ok {
+		return errInvalidProof
+	}
+
+	ev, err := c.g.NewElement().Decode(signed.Element)
+	if err != nil {
+		return fmt.Errorf("tokens: decoding signed token: %w", err)
+	}
+
+	unblinded := ev.Mult(c.pending.blind.Invert())
+
+	c.token = &redeemableToken{nonce: c.pending.nonce, key: unblinded.Bytes(), epochID: signed.EpochID}
+	c.pending = nil
+
+	return nil
+}
+
+// Spend produces a SpentToken that binds message to the unblinded token, for presentation to any
+// verifier holding the issuing server's key. Each token may only be spent once; callers must not
+// reuse it across calls.
+func (c *Client) Spend(message []byte) ([]byte, error) {
+	if c.token == nil {
+		return nil, fmt.Errorf("tokens: spend called without a redeemable token")
+	}
+
+	mac := hmac.New(sha256.New, c.token.key)
+	mac.Write(message)
+
+	spent := &SpentToken{Nonce: c.token.nonce, MAC: mac.Sum(nil), EpochID: c.token.epochID}
+	c.token = nil
+
+	return spent.Encode(), nil
+}
@@ -0,0 +1,10 @@
+package tokens
+
+import "errors"
+
+var (
+	errShortToken   = errors.New("tokens: truncated spent token")
+	errDoubleSpend  = errors.New("tokens: token nonce already redeemed")
+	errInvalidMAC   = errors.New("tokens: invalid token MAC")
+	errInvalidProof = errors.New("tokens: invalid DLEQ proof on signed token")
+)
@@ -0,0 +1,74 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/bytemare/voprf"
+)
+
+// Server issues and redeems anonymous tokens on top of a voprf.Server running in Verifiable mode.
+type Server struct {
+	oprf  *voprf.Server
+	spent SpentStore
+}
+
+// NewServer wraps an existing voprf.Server with token issuance and redemption. The server must have
+// been created in Verifiable mode, since Client.Unblind checks the DLEQ proof that IssueToken attaches
+// and Base mode attaches none. If store is nil, redeemed nonces are tracked in memory only.
+func NewServer(oprf *voprf.Server, store SpentStore) *Server {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+
+	return &Server{oprf: oprf, spent: store}
+}
+
+// IssueToken blind-signs a client-blinded token. It is a thin wrapper around Evaluate that returns
+// the evaluated element and its DLEQ proof in a form the client can check and unblind into a
+// redeemable token.
+func (s *Server) IssueToken(blindedToken []byte) (*SignedToken, error) {
+	ev, err := s.oprf.Evaluate(blindedToken)
+	if err != nil {
+		return nil, fmt.Errorf("tokens: issuing token: %w", err)
+	}
+
+	return &SignedToken{
+		Element: ev.Elements[0],
+		ProofC:  ev.ProofC,
+		ProofS:  ev.ProofS,
+		EpochID: ev.EpochID,
+	}, nil
+}
+
+// RedeemToken verifies that spentToken authenticates message and that its nonce has not been
+// redeemed before. The MAC is recomputed as HMAC-SHA256 over message, keyed by the server's
+// re-derived per-token key k = H(t)^sk - derived under the same epoch the token recorded it was
+// issued under, since the key is epoch-specific - and compared in constant time against the
+// client's binding of message.
+func (s *Server) RedeemToken(spentToken, message []byte) error {
+	tok, err := DecodeSpentToken(spentToken)
+	if err != nil {
+		return fmt.Errorf("tokens: redeeming token: %w", err)
+	}
+
+	key, err := s.oprf.EvaluatePRF(tok.Nonce, tok.EpochID)
+	if err != nil {
+		return fmt.Errorf("tokens: redeeming token: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	expected := mac.Sum(nil)
+
+	if !hmac.Equal(expected, tok.MAC) {
+		return errInvalidMAC
+	}
+
+	if !s.spent.CheckAndSet(tok.Nonce) {
+		return errDoubleSpend
+	}
+
+	return nil
+}
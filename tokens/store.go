@@ -0,0 +1,34 @@
+package tokens
+
+import "sync"
+
+// SpentStore tracks which token nonces have already been redeemed, so a token can only be spent once.
+type SpentStore interface {
+	// CheckAndSet atomically marks nonce as spent, returning false if it was already spent before.
+	CheckAndSet(nonce []byte) (fresh bool)
+}
+
+// memoryStore is a SpentStore backed by an in-memory map. It is not persisted across restarts.
+type memoryStore struct {
+	mu    sync.Mutex
+	spent map[string]struct{}
+}
+
+// NewMemoryStore returns a SpentStore that keeps spent nonces in memory for the lifetime of the process.
+func NewMemoryStore() SpentStore {
+	return &memoryStore{spent: make(map[string]struct{})}
+}
+
+func (m *memoryStore) CheckAndSet(nonce []byte) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := string(nonce)
+	if _, ok := m.spent[key]; ok {
+		return false
+	}
+
+	m.spent[key] = struct{}{}
+
+	return true
+}
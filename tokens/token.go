@@ -0,0 +1,61 @@
+// Package tokens implements Privacy Pass-style anonymous tokens on top of a VOPRF voprf.Server
+// in Verifiable mode: a server issues blind-signed tokens it cannot link to their later redemption,
+// and a verifier holding the same key can still detect a token being spent twice.
+package tokens
+
+import "encoding/binary"
+
+// SignedToken is the server's response to a blinded token: the evaluated element and the DLEQ proof
+// attesting it was computed with the issuer's key, carried as separate fields so a client can
+// unblind the element without needing to parse a proof out of it first. EpochID records which of the
+// issuer's key epochs signed it, since the proof's transcript is bound to that epoch and won't verify
+// against the wrong one.
+type SignedToken struct {
+	Element []byte
+	ProofC  []byte
+	ProofS  []byte
+	EpochID uint32
+}
+
+// SpentToken is what a client presents to redeem a token: the token's nonce, the MAC binding it to
+// the redeemed message, and the epoch it was issued under. EpochID is needed because the MAC key is
+// H(nonce)^sk_epoch - specific to the issuing epoch - so a server with more than one epoch in its
+// keyring (e.g. mid-rotation) must redeem against the same one that issued the token.
+type SpentToken struct {
+	Nonce   []byte
+	MAC     []byte
+	EpochID uint32
+}
+
+// Encode serializes a SpentToken as the epoch ID, followed by the len-prefixed nonce, followed by
+// the MAC.
+func (s *SpentToken) Encode() []byte {
+	out := make([]byte, 4+4+len(s.Nonce)+len(s.MAC))
+	binary.BigEndian.PutUint32(out[:4], s.EpochID)
+	binary.BigEndian.PutUint32(out[4:8], uint32(len(s.Nonce)))
+	copy(out[8:], s.Nonce)
+	copy(out[8+len(s.Nonce):], s.MAC)
+
+	return out
+}
+
+// DecodeSpentToken parses the wire format produced by SpentToken.Encode.
+func DecodeSpentToken(b []byte) (*SpentToken, error) {
+	if len(b) < 8 {
+		return nil, errShortToken
+	}
+
+	epochID := binary.BigEndian.Uint32(b[:4])
+	n := binary.BigEndian.Uint32(b[4:8])
+	b = b[8:]
+
+	if uint32(len(b)) < n {
+		return nil, errShortToken
+	}
+
+	return &SpentToken{
+		Nonce:   b[:n],
+		MAC:     b[n:],
+		EpochID: epochID,
+	}, nil
+}
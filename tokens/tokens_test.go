@@ -0,0 +1,97 @@
+package tokens
+
+import (
+	"testing"
+
+	"github.com/bytemare/voprf"
+	"github.com/bytemare/voprf/internal/testgroup"
+)
+
+func TestIssueRedeemRoundTrip(t *testing.T) {
+	g := testgroup.New()
+
+	oprfServer := voprf.NewServer(g, voprf.Verifiable)
+	server := NewServer(oprfServer, nil)
+	client := NewClient(g)
+
+	blinded, err := client.GenBlindedToken()
+	if err != nil {
+		t.Fatalf("GenBlindedToken: %v", err)
+	}
+
+	signed, err := server.IssueToken(blinded)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	if err := client.Unblind(signed, oprfServer.PublicKey()); err != nil {
+		t.Fatalf("Unblind: %v", err)
+	}
+
+	spent, err := client.Spend([]byte("message"))
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	if err := server.RedeemToken(spent, []byte("message")); err != nil {
+		t.Fatalf("RedeemToken rejected a freshly issued token: %v", err)
+	}
+}
+
+func TestRedeemTokenRejectsDoubleSpend(t *testing.T) {
+	g := testgroup.New()
+
+	oprfServer := voprf.NewServer(g, voprf.Verifiable)
+	server := NewServer(oprfServer, nil)
+	client := NewClient(g)
+
+	blinded, err := client.GenBlindedToken()
+	if err != nil {
+		t.Fatalf("GenBlindedToken: %v", err)
+	}
+
+	signed, err := server.IssueToken(blinded)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	if err := client.Unblind(signed, oprfServer.PublicKey()); err != nil {
+		t.Fatalf("Unblind: %v", err)
+	}
+
+	spent, err := client.Spend([]byte("message"))
+	if err != nil {
+		t.Fatalf("Spend: %v", err)
+	}
+
+	if err := server.RedeemToken(spent, []byte("message")); err != nil {
+		t.Fatalf("RedeemToken rejected the first redemption: %v", err)
+	}
+
+	if err := server.RedeemToken(spent, []byte("message")); err == nil {
+		t.Fatal("RedeemToken accepted a second redemption of the same token")
+	}
+}
+
+func TestUnblindRejectsWrongIssuerKey(t *testing.T) {
+	g := testgroup.New()
+
+	oprfServer := voprf.NewServer(g, voprf.Verifiable)
+	impostor := voprf.NewServer(g, voprf.Verifiable)
+	server := NewServer(oprfServer, nil)
+	client := NewClient(g)
+
+	blinded, err := client.GenBlindedToken()
+	if err != nil {
+		t.Fatalf("GenBlindedToken: %v", err)
+	}
+
+	signed, err := server.IssueToken(blinded)
+	if err != nil {
+		t.Fatalf("IssueToken: %v", err)
+	}
+
+	if err := client.Unblind(signed, impostor.PublicKey()); err == nil {
+		t.Fatal("Unblind accepted a token checked against the wrong issuer's key")
+	}
+}
@@ -0,0 +1,85 @@
+package voprf
+
+import (
+	"testing"
+
+	"github.com/bytemare/voprf/internal/testgroup"
+)
+
+func TestFinalizeAcceptsValidProof(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Verifiable)
+	c := NewClient(g, Verifiable, s.keyring.activeEpoch().publicKey)
+
+	blinded, err := c.Blind([]byte("input"))
+	if err != nil {
+		t.Fatalf("Blind: %v", err)
+	}
+
+	ev, err := s.Evaluate(blinded)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	out, err := c.Finalize(ev, nil)
+	if err != nil {
+		t.Fatalf("Finalize rejected a valid evaluation: %v", err)
+	}
+
+	if !s.VerifyFinalize([]byte("input"), out, nil) {
+		t.Fatal("server did not recognize the client's finalized output")
+	}
+}
+
+func TestFinalizeRejectsTamperedProof(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Verifiable)
+	c := NewClient(g, Verifiable, s.keyring.activeEpoch().publicKey)
+
+	blinded, err := c.Blind([]byte("input"))
+	if err != nil {
+		t.Fatalf("Blind: %v", err)
+	}
+
+	ev, err := s.Evaluate(blinded)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	tamperedProofS := append([]byte{}, ev.ProofS...)
+	tamperedProofS[0] ^= 0xff
+
+	tampered := &EpochEvaluation{
+		Evaluation: &Evaluation{Elements: ev.Elements, ProofC: ev.ProofC, ProofS: tamperedProofS},
+		EpochID:    ev.EpochID,
+	}
+
+	if _, err := c.Finalize(tampered, nil); err == nil {
+		t.Fatal("Finalize accepted a tampered proof")
+	}
+}
+
+func TestFinalizeRejectsWrongIssuerKey(t *testing.T) {
+	g := testgroup.New()
+
+	s := NewServer(g, Verifiable)
+	impostor := NewServer(g, Verifiable)
+
+	c := NewClient(g, Verifiable, impostor.keyring.activeEpoch().publicKey)
+
+	blinded, err := c.Blind([]byte("input"))
+	if err != nil {
+		t.Fatalf("Blind: %v", err)
+	}
+
+	ev, err := s.Evaluate(blinded)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	if _, err := c.Finalize(ev, nil); err == nil {
+		t.Fatal("Finalize accepted a proof checked against the wrong server's key")
+	}
+}